@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FileConfig is the --config file surface, mirroring the ffufrc pattern:
+// top-level defaults, a [[target]] array of per-URL/per-host overrides,
+// and named [profiles.<name>] sections selectable via --profile.
+type FileConfig struct {
+	Threads     int64                    `toml:"threads"`
+	Rate        float64                  `toml:"rate"`
+	Timeout     int                      `toml:"timeout"`
+	Format      string                   `toml:"format"`
+	Output      string                   `toml:"output"`
+	UserAgent   string                   `toml:"user_agent"`
+	Headers     map[string]string        `toml:"headers"`
+	Cookies     map[string]string        `toml:"cookies"`
+	Proxy       string                   `toml:"proxy"`
+	InsecureTLS bool                     `toml:"insecure_tls"`
+	Targets     []TargetOverride         `toml:"target"`
+	Profiles    map[string]ProfileConfig `toml:"profiles"`
+}
+
+// TargetOverride customizes behavior for URLs matching URL exactly or Host
+// as a glob (e.g. "*.internal.example.com").
+type TargetOverride struct {
+	URL               string            `toml:"url"`
+	Host              string            `toml:"host"`
+	Headers           map[string]string `toml:"headers"`
+	Cookies           map[string]string `toml:"cookies"`
+	Auth              string            `toml:"auth"`
+	DisabledPatterns  []string          `toml:"disabled_patterns"`
+	ExtraPatternFiles []string          `toml:"extra_pattern_files"`
+}
+
+// ProfileConfig overlays a named set of defaults, selected with --profile.
+type ProfileConfig struct {
+	Threads int64   `toml:"threads"`
+	Rate    float64 `toml:"rate"`
+	Timeout int     `toml:"timeout"`
+	Format  string  `toml:"format"`
+	Output  string  `toml:"output"`
+}
+
+// loadFileConfig parses a TOML config file from disk.
+//
+// The tool has always skipped TLS verification by default (it's routinely
+// pointed at self-signed/internal HTTPS hosts), so insecure_tls defaults to
+// true here too -- it only flips to false if a config file explicitly sets
+// insecure_tls = false, not merely by omitting the key (whose Go zero value
+// would otherwise silently turn verification back on).
+func loadFileConfig(configPath string) (*FileConfig, error) {
+	var fc FileConfig
+	md, err := toml.DecodeFile(configPath, &fc)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing config file: %v", err)
+	}
+	if !md.IsDefined("insecure_tls") {
+		fc.InsecureTLS = true
+	}
+	return &fc, nil
+}
+
+// applyProfile overlays fc.Profiles[name] onto fc's top-level defaults.
+func (fc *FileConfig) applyProfile(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	profile, ok := fc.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+
+	if profile.Threads != 0 {
+		fc.Threads = profile.Threads
+	}
+	if profile.Rate != 0 {
+		fc.Rate = profile.Rate
+	}
+	if profile.Timeout != 0 {
+		fc.Timeout = profile.Timeout
+	}
+	if profile.Format != "" {
+		fc.Format = profile.Format
+	}
+	if profile.Output != "" {
+		fc.Output = profile.Output
+	}
+
+	return nil
+}
+
+// TargetSettings is the fully-merged, per-URL configuration: file-level
+// defaults overlaid with whatever [[target]] entries match.
+type TargetSettings struct {
+	Headers     map[string]string
+	Cookies     map[string]string
+	Proxy       string
+	InsecureTLS bool
+	UserAgent   string
+	Timeout     time.Duration
+	Disabled    map[string]bool
+}
+
+// matchingTargets returns the [[target]] entries whose URL or Host glob
+// matches targetURL, in file order (later entries win on conflict).
+func (fc *FileConfig) matchingTargets(targetURL string) []TargetOverride {
+	host := ""
+	if parsed, err := url.Parse(targetURL); err == nil {
+		host = parsed.Host
+	}
+
+	var matches []TargetOverride
+	for _, target := range fc.Targets {
+		switch {
+		case target.URL != "":
+			if target.URL == targetURL {
+				matches = append(matches, target)
+			}
+		case target.Host != "":
+			if matched, _ := path.Match(target.Host, host); matched {
+				matches = append(matches, target)
+			}
+		}
+	}
+
+	return matches
+}
+
+// settingsFor resolves the effective settings for a single URL, used to
+// build its http.Client and http.Request.
+func (fc *FileConfig) settingsFor(targetURL string) TargetSettings {
+	settings := TargetSettings{
+		Headers:     map[string]string{},
+		Cookies:     map[string]string{},
+		Proxy:       fc.Proxy,
+		InsecureTLS: fc.InsecureTLS,
+		UserAgent:   fc.UserAgent,
+		Timeout:     time.Duration(fc.Timeout) * time.Second,
+		Disabled:    map[string]bool{},
+	}
+
+	for k, v := range fc.Headers {
+		settings.Headers[k] = v
+	}
+	for k, v := range fc.Cookies {
+		settings.Cookies[k] = v
+	}
+
+	for _, target := range fc.matchingTargets(targetURL) {
+		for k, v := range target.Headers {
+			settings.Headers[k] = v
+		}
+		for k, v := range target.Cookies {
+			settings.Cookies[k] = v
+		}
+		if target.Auth != "" {
+			settings.Headers["Authorization"] = target.Auth
+		}
+		for _, name := range target.DisabledPatterns {
+			settings.Disabled[name] = true
+		}
+	}
+
+	return settings
+}
+
+// extraPatternsFor loads any extra_pattern_files declared by [[target]]
+// entries matching targetURL, on top of the globally loaded patterns.json.
+func (fc *FileConfig) extraPatternsFor(targetURL string) []Pattern {
+	var extra []Pattern
+	for _, target := range fc.matchingTargets(targetURL) {
+		for _, file := range target.ExtraPatternFiles {
+			patterns, err := loadPatterns(file)
+			if err != nil {
+				continue
+			}
+			extra = append(extra, patterns...)
+		}
+	}
+	return extra
+}
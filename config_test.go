@@ -0,0 +1,75 @@
+package main
+
+import "testing"
+
+func TestMatchingTargetsURLExact(t *testing.T) {
+	fc := &FileConfig{
+		Targets: []TargetOverride{
+			{URL: "https://example.com/a", Auth: "Bearer a"},
+			{URL: "https://example.com/b", Auth: "Bearer b"},
+		},
+	}
+
+	matches := fc.matchingTargets("https://example.com/a")
+	if len(matches) != 1 || matches[0].Auth != "Bearer a" {
+		t.Fatalf("matchingTargets returned %+v, want exactly the url=a entry", matches)
+	}
+}
+
+func TestMatchingTargetsHostGlob(t *testing.T) {
+	fc := &FileConfig{
+		Targets: []TargetOverride{
+			{Host: "*.internal.example.com", Auth: "Bearer internal"},
+			{Host: "other.example.com", Auth: "Bearer other"},
+		},
+	}
+
+	matches := fc.matchingTargets("https://api.internal.example.com/path")
+	if len(matches) != 1 || matches[0].Auth != "Bearer internal" {
+		t.Fatalf("matchingTargets returned %+v, want exactly the internal glob entry", matches)
+	}
+}
+
+func TestSettingsForMergesTargetOverrides(t *testing.T) {
+	fc := &FileConfig{
+		Headers: map[string]string{"X-Default": "1"},
+		Targets: []TargetOverride{
+			{
+				Host:             "example.com",
+				Headers:          map[string]string{"X-Target": "2"},
+				Auth:             "Bearer tok",
+				DisabledPatterns: []string{"AWSKey"},
+			},
+		},
+	}
+
+	settings := fc.settingsFor("https://example.com/path")
+
+	if settings.Headers["X-Default"] != "1" || settings.Headers["X-Target"] != "2" {
+		t.Fatalf("settingsFor headers = %+v, want both default and target headers merged", settings.Headers)
+	}
+	if settings.Headers["Authorization"] != "Bearer tok" {
+		t.Fatalf("settingsFor Authorization = %q, want target auth applied", settings.Headers["Authorization"])
+	}
+	if !settings.Disabled["AWSKey"] {
+		t.Fatalf("settingsFor Disabled = %+v, want AWSKey disabled", settings.Disabled)
+	}
+}
+
+func TestSettingsForNoMatchKeepsDefaultsOnly(t *testing.T) {
+	fc := &FileConfig{
+		InsecureTLS: true,
+		Targets: []TargetOverride{
+			{Host: "other.example.com", Auth: "Bearer other"},
+		},
+	}
+
+	settings := fc.settingsFor("https://example.com/path")
+
+	if !settings.InsecureTLS {
+		t.Fatalf("settingsFor InsecureTLS = false, want the file-level default to carry through")
+	}
+	if _, ok := settings.Headers["Authorization"]; ok {
+		t.Fatalf("settingsFor applied an Authorization header from a non-matching target")
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Crawler expands a single fetched document into the script srcs, preload
+// link hrefs, and sourcemaps (including sourceMappingURL comments in inline
+// scripts) it references, fetching each as an independent target under the
+// same cfg.sem bound. It guards against cycles and
+// runaway expansion with a visited set, a depth cap, and a hostname scope.
+type Crawler struct {
+	maxDepth int
+	scope    *regexp.Regexp
+	visited  sync.Map
+}
+
+var sourceMappingURLRegex = regexp.MustCompile(`//[#@]\s*sourceMappingURL=(\S+)`)
+
+// NewCrawler builds a Crawler bounded by depth and restricted to hostnames
+// matching scopePattern (empty matches everything).
+func NewCrawler(depth int, scopePattern string) (*Crawler, error) {
+	var scope *regexp.Regexp
+	if scopePattern != "" {
+		var err error
+		scope, err = regexp.Compile(scopePattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --crawl-scope regex: %v", err)
+		}
+	}
+
+	return &Crawler{maxDepth: depth, scope: scope}, nil
+}
+
+func (c *Crawler) inScope(target string) bool {
+	if c.scope == nil {
+		return true
+	}
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+	return c.scope.MatchString(parsed.Host)
+}
+
+// visit marks target as seen, returning false if it had already been
+// visited.
+func (c *Crawler) visit(target string) bool {
+	_, loaded := c.visited.LoadOrStore(target, true)
+	return !loaded
+}
+
+// Expand discovers the links reachable from baseURL's HTML/JS body and
+// scans each of them with cfg's pattern/entropy detectors,
+// recursing up to c.maxDepth.
+func (c *Crawler) Expand(cfg *Config, baseURL string, body []byte, depth int) {
+	if depth >= c.maxDepth {
+		return
+	}
+
+	for _, link := range c.extractLinks(baseURL, body) {
+		if !c.inScope(link) || !c.visit(link) {
+			continue
+		}
+
+		c.fetchAndScan(cfg, link, depth+1)
+	}
+}
+
+func (c *Crawler) extractLinks(baseURL string, body []byte) []string {
+	var links []string
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(string(body)))
+	if err == nil {
+		doc.Find("script[src]").Each(func(_ int, sel *goquery.Selection) {
+			if src, ok := sel.Attr("src"); ok {
+				links = append(links, src)
+			}
+		})
+		doc.Find("link[href]").Each(func(_ int, sel *goquery.Selection) {
+			if rel, _ := sel.Attr("rel"); rel == "preload" || rel == "modulepreload" {
+				if href, ok := sel.Attr("href"); ok {
+					links = append(links, href)
+				}
+			}
+		})
+		doc.Find("script:not([src])").Each(func(_ int, sel *goquery.Selection) {
+			if m := sourceMappingURLRegex.FindStringSubmatch(sel.Text()); m != nil {
+				links = append(links, m[1])
+			}
+		})
+	}
+
+	if m := sourceMappingURLRegex.FindAllStringSubmatch(string(body), -1); m != nil {
+		for _, match := range m {
+			links = append(links, match[1])
+		}
+	}
+
+	resolved := make([]string, 0, len(links))
+	for _, link := range links {
+		if abs := resolveScraperURL(baseURL, link); abs != "" {
+			resolved = append(resolved, abs)
+		}
+	}
+
+	return resolved
+}
+
+// fetchAndScan pulls target down, runs the same pattern/entropy pass
+// (scanAndReport) that processURL does, and (when it's a sourcemap) expands
+// into its embedded sources too.
+func (c *Crawler) fetchAndScan(cfg *Config, target string, depth int) {
+	if err := cfg.sem.Acquire(context.Background(), 1); err != nil {
+		return
+	}
+	// The slot covers this target's own fetch and scan only; it is released
+	// before recursing so a chain of nested links cannot wait on itself.
+	released := false
+	release := func() {
+		if !released {
+			released = true
+			cfg.sem.Release(1)
+		}
+	}
+	defer release()
+
+	settings := TargetSettings{InsecureTLS: true}
+	if cfg.fileConfig != nil {
+		settings = cfg.fileConfig.settingsFor(target)
+	}
+
+	client, err := newClient(settings)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return
+	}
+
+	start := time.Now()
+	resp, err := cfg.doRequest(client, req)
+	if err != nil {
+		if !cfg.silent {
+			fmt.Fprintf(os.Stderr, "Error: failed to fetch crawled URL %s: %v\n", target, err)
+		}
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		if !cfg.silent {
+			fmt.Fprintf(os.Stderr, "Error: got status code %d for crawled URL %s\n", resp.StatusCode, target)
+		}
+		return
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		if !cfg.silent {
+			fmt.Fprintf(os.Stderr, "Error: failed to read crawled URL %s: %v\n", target, err)
+		}
+		return
+	}
+
+	if strings.HasSuffix(target, ".map") {
+		c.scanSourceMap(cfg, target, body, start)
+	} else {
+		cfg.scanAndReport(target, body, start)
+	}
+
+	release()
+	c.Expand(cfg, target, body, depth)
+}
+
+type sourceMap struct {
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent"`
+}
+
+// scanSourceMap decodes a .map file's sources/sourcesContent pairs and
+// scans each embedded original file as if it were its own document,
+// tagging the synthetic URL with a #source=... fragment for traceability.
+func (c *Crawler) scanSourceMap(cfg *Config, mapURL string, body []byte, start time.Time) {
+	var sm sourceMap
+	if err := json.Unmarshal(body, &sm); err != nil {
+		return
+	}
+
+	for i, content := range sm.SourcesContent {
+		if content == "" {
+			continue
+		}
+
+		source := mapURL
+		if i < len(sm.Sources) {
+			source = mapURL + "#source=" + sm.Sources[i]
+		}
+
+		cfg.scanAndReport(source, []byte(content), start)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "testing"
+
+func TestShannonEntropy(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want float64
+	}{
+		{"empty", "", 0},
+		{"single repeated char", "aaaaaaaa", 0},
+		{"two equally likely chars", "abababab", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shannonEntropy(tt.in); got != tt.want {
+				t.Errorf("shannonEntropy(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShannonEntropyIncreasesWithRandomness(t *testing.T) {
+	low := shannonEntropy("aaaaaaaaaa")
+	high := shannonEntropy("aB3$kZ9!qW")
+	if !(high > low) {
+		t.Errorf("expected a high-variety string to score higher entropy than a repeated one, got low=%v high=%v", low, high)
+	}
+}
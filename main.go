@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"os"
 	"regexp"
 	"strings"
@@ -27,10 +28,18 @@ type Pattern struct {
 type PatternsJSON map[string]string
 
 type Result struct {
-	Type     string
-	Data     string
-	URL      string
-	TimeUsed time.Duration
+	Type             string
+	Data             string
+	URL              string
+	TimeUsed         time.Duration
+	ValidationStatus Status
+	ValidationMeta   map[string]string
+
+	// scraperAction is the originating ScraperRule's Action ("log" or
+	// "store"), unset for everything but scraper results. It is not part
+	// of any formatter's output, only consulted by processURL to decide
+	// whether a match is printed or just written to the output file.
+	scraperAction string
 }
 
 type Config struct {
@@ -43,6 +52,13 @@ type Config struct {
 	outputFormat string
 	foundSecrets sync.Map
 	sem          *semaphore.Weighted
+	scraper      *Scraper
+	entropy      *EntropyDetector
+	validators   *ValidatorSet
+	crawler      *Crawler
+	fileConfig   *FileConfig
+	rateLimiter  *RateLimiter
+	maxRetries   int
 }
 
 // ResultFormatter interface for different output formats
@@ -55,8 +71,23 @@ type ResultFormatter interface {
 type TextFormatter struct{}
 
 func (f TextFormatter) FormatResult(result Result) string {
-	return fmt.Sprintf("[+] Type: %s, Data: %s, URL: %s (Found in: %s)",
+	base := fmt.Sprintf("[+] Type: %s, Data: %s, URL: %s (Found in: %s)",
 		result.Type, result.Data, result.URL, result.TimeUsed)
+	if result.ValidationStatus != "" {
+		base += fmt.Sprintf(" [Validation: %s%s]", result.ValidationStatus, formatValidationMeta(result.ValidationMeta))
+	}
+	return base
+}
+
+func formatValidationMeta(meta map[string]string) string {
+	if len(meta) == 0 {
+		return ""
+	}
+	var parts []string
+	for k, v := range meta {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, v))
+	}
+	return " (" + strings.Join(parts, ", ") + ")"
 }
 
 func (f TextFormatter) FileExtension() string {
@@ -64,10 +95,12 @@ func (f TextFormatter) FileExtension() string {
 }
 
 type JSONOutput struct {
-	Type     string        `json:"type"`
-	Data     string        `json:"data"`
-	URL      string        `json:"url"`
-	Duration time.Duration `json:"duration"`
+	Type             string            `json:"type"`
+	Data             string            `json:"data"`
+	URL              string            `json:"url"`
+	Duration         time.Duration     `json:"duration"`
+	ValidationStatus Status            `json:"validation_status,omitempty"`
+	ValidationMeta   map[string]string `json:"validation_meta,omitempty"`
 }
 
 // JSON formatter
@@ -75,16 +108,13 @@ type JSONOutput struct {
 type JSONFormatter struct{}
 
 func (f JSONFormatter) FormatResult(result Result) string {
-	output := struct {
-		Type     string        `json:"type"`
-		Data     string        `json:"data"`
-		URL      string        `json:"url"`
-		Duration time.Duration `json:"duration"`
-	}{
-		Type:     result.Type,
-		Data:     result.Data,
-		URL:      result.URL,
-		Duration: result.TimeUsed,
+	output := JSONOutput{
+		Type:             result.Type,
+		Data:             result.Data,
+		URL:              result.URL,
+		Duration:         result.TimeUsed,
+		ValidationStatus: result.ValidationStatus,
+		ValidationMeta:   result.ValidationMeta,
 	}
 	jsonData, _ := json.Marshal(output)
 	return string(jsonData)
@@ -98,11 +128,12 @@ func (f JSONFormatter) FileExtension() string {
 type CSVFormatter struct{}
 
 func (f CSVFormatter) FormatResult(result Result) string {
-	return fmt.Sprintf("%s,%s,%s,%s",
+	return fmt.Sprintf("%s,%s,%s,%s,%s",
 		result.Type,
 		result.Data,
 		result.URL,
-		result.TimeUsed)
+		result.TimeUsed,
+		result.ValidationStatus)
 }
 
 func (f CSVFormatter) FileExtension() string {
@@ -134,6 +165,24 @@ Options:
     --output    <file>    Output file to write results
     --format    <format>  Output format: txt, json, csv (default: txt)
     --silent              Suppress banner and status messages
+    --scraper-dir <dir>   Directory of scraper rule files (JSON/YAML)
+    --scrapers  <names>   Scraper rules to run: all|<name1,name2> (default: all)
+    --entropy                     Enable high-entropy secret detection
+    --entropy-b64-threshold <n>   Shannon entropy threshold for base64 tokens (default: 4.5)
+    --entropy-hex-threshold <n>   Shannon entropy threshold for hex tokens (default: 3.0)
+    --entropy-min-len <n>         Minimum token length for entropy detection (default: 20)
+    --entropy-exclude <regexes>   Comma-separated regexes of benign tokens to exclude
+    --validate                    Actively validate discovered secrets against provider APIs
+                                   (AWS/Twilio are format-checked only: a regex match never
+                                   carries the paired secret/SID a real signed call needs)
+    --validate-timeout <dur>      Timeout for each provider validation request (default: 10s)
+    --crawl                       Recursively crawl JS/sourcemap references reachable from each URL
+    --crawl-depth <n>             Maximum recursion depth for --crawl (default: 2)
+    --crawl-scope <regex>         Regex restricting --crawl expansion to matching hostnames
+    --config <file>               TOML config file with defaults and per-target overrides
+    --profile <name>               Named [profiles.<name>] section to apply from --config
+    --rate <req/sec>               Global and per-host requests/sec limit (default: 0, unlimited)
+    --max-retries <n>              Maximum retries on 429/503 before giving up on a URL (default: 3)
     --help                Show this help message
 
 Example:
@@ -219,12 +268,42 @@ func maskData(data string, visibleChars int) string {
 	return strings.Repeat("*", len(data)-visibleChars) + data[len(data)-visibleChars:]
 }
 
+// newClient builds an *http.Client honoring a target's effective proxy,
+// TLS, and timeout settings, centralizing what used to be a hardcoded
+// client per request so per-target config actually takes effect.
+func newClient(settings TargetSettings) (*http.Client, error) {
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: settings.InsecureTLS},
+	}
+
+	if settings.Proxy != "" {
+		proxyURL, err := url.Parse(settings.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %v", settings.Proxy, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	timeout := settings.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &http.Client{Timeout: timeout, Transport: transport}, nil
+}
+
 func (cfg *Config) processURL(url string) error {
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		},
+	settings := TargetSettings{InsecureTLS: true, UserAgent: "Go-Fetch-Secrets/2.0"}
+	if cfg.fileConfig != nil {
+		settings = cfg.fileConfig.settingsFor(url)
+		if settings.UserAgent == "" {
+			settings.UserAgent = "Go-Fetch-Secrets/2.0"
+		}
+	}
+
+	client, err := newClient(settings)
+	if err != nil {
+		return fmt.Errorf("failed to build client for %s: %v", url, err)
 	}
 
 	req, err := http.NewRequest("GET", url, nil)
@@ -232,10 +311,16 @@ func (cfg *Config) processURL(url string) error {
 		return fmt.Errorf("failed to create request for %s: %v", url, err)
 	}
 
-	req.Header.Set("User-Agent", "Go-Fetch-Secrets/2.0")
+	req.Header.Set("User-Agent", settings.UserAgent)
+	for name, value := range settings.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range settings.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
 
 	start := time.Now()
-	resp, err := client.Do(req)
+	resp, err := cfg.doRequest(client, req)
 	if err != nil {
 		return fmt.Errorf("failed to fetch %s: %v", url, err)
 	}
@@ -250,8 +335,48 @@ func (cfg *Config) processURL(url string) error {
 		return fmt.Errorf("failed to read response from %s: %v", url, err)
 	}
 
+	cfg.scanAndReport(url, body, start)
+
+	if cfg.scraper != nil {
+		for _, result := range cfg.scraper.Run(cfg, url, resp.Header, body, start) {
+			if _, exists := cfg.foundSecrets.LoadOrStore(result.Type+result.Data, true); exists {
+				continue
+			}
+			if result.scraperAction == "store" {
+				cfg.storeResult(result)
+			} else {
+				cfg.printResult(result)
+			}
+		}
+	}
+
+	if cfg.crawler != nil {
+		cfg.crawler.Expand(cfg, url, body, 0)
+	}
+
+	return nil
+}
+
+// scanAndReport runs the regex pattern engine and (if enabled) the entropy
+// detector over body, reporting any new findings against targetURL. It is
+// shared by processURL and the crawl mode, which both need to apply the
+// same detection pipeline to a fetched document.
+func (cfg *Config) scanAndReport(targetURL string, body []byte, start time.Time) {
 	content := string(body)
-	for _, pattern := range cfg.patterns {
+
+	patterns := cfg.patterns
+	disabled := map[string]bool{}
+	if cfg.fileConfig != nil {
+		settings := cfg.fileConfig.settingsFor(targetURL)
+		disabled = settings.Disabled
+		patterns = append(append([]Pattern{}, patterns...), cfg.fileConfig.extraPatternsFor(targetURL)...)
+	}
+
+	for _, pattern := range patterns {
+		if disabled[pattern.Name] {
+			continue
+		}
+
 		regex, err := regexp.Compile(pattern.Pattern)
 		if err != nil {
 			continue
@@ -266,15 +391,28 @@ func (cfg *Config) processURL(url string) error {
 			result := Result{
 				Type:     pattern.Name,
 				Data:     match,
-				URL:      url,
+				URL:      targetURL,
 				TimeUsed: time.Since(start),
 			}
 
+			if cfg.validators != nil {
+				result.ValidationStatus, result.ValidationMeta = cfg.validators.Validate(pattern.Name, match)
+			}
+
 			cfg.printResult(result)
 		}
 	}
 
-	return nil
+	if cfg.entropy != nil {
+		for _, result := range cfg.entropy.Scan(content) {
+			if _, exists := cfg.foundSecrets.LoadOrStore(result.Type+result.Data, true); exists {
+				continue
+			}
+			result.URL = targetURL
+			result.TimeUsed = time.Since(start)
+			cfg.printResult(result)
+		}
+	}
 }
 
 func (cfg *Config) printResult(result Result) {
@@ -294,7 +432,20 @@ func (cfg *Config) printResult(result Result) {
 		}
 	}
 
-	// Write to output file if specified
+	cfg.writeResultToFile(result)
+}
+
+// storeResult records result in the output file only, with no stdout
+// output. It backs ScraperRule.Action == "store", for rules whose matches
+// should be recorded without cluttering the terminal.
+func (cfg *Config) storeResult(result Result) {
+	cfg.writeResultToFile(result)
+}
+
+func (cfg *Config) writeResultToFile(result Result) {
+	formatter := getFormatter(cfg.outputFormat)
+	output := formatter.FormatResult(result)
+
 	if cfg.outputFile != "" {
 		f, err := os.OpenFile(cfg.outputFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
@@ -307,7 +458,7 @@ func (cfg *Config) printResult(result Result) {
 		if cfg.outputFormat == "csv" {
 			fileInfo, err := f.Stat()
 			if err == nil && fileInfo.Size() == 0 {
-				fmt.Fprintln(f, "Type,Data,URL,Duration")
+				fmt.Fprintln(f, "Type,Data,URL,Duration,ValidationStatus")
 			}
 		}
 
@@ -342,6 +493,22 @@ func main() {
 	format := flag.String("format", "txt", "Output format (txt, json, csv)")
 	silent := flag.Bool("silent", false, "Suppress banner and status messages")
 	helpFlag := flag.Bool("help", false, "Show help message")
+	scraperDir := flag.String("scraper-dir", "", "Directory of scraper rule files (JSON/YAML)")
+	scrapers := flag.String("scrapers", "all", "Scraper rules to run: all|<name1,name2>")
+	entropyEnabled := flag.Bool("entropy", false, "Enable high-entropy secret detection")
+	entropyB64Threshold := flag.Float64("entropy-b64-threshold", 4.5, "Shannon entropy threshold for base64-charset tokens")
+	entropyHexThreshold := flag.Float64("entropy-hex-threshold", 3.0, "Shannon entropy threshold for hex-charset tokens")
+	entropyMinLen := flag.Int("entropy-min-len", 20, "Minimum token length considered for entropy detection")
+	entropyExclude := flag.String("entropy-exclude", "", "Comma-separated regexes of benign tokens to exclude")
+	validate := flag.Bool("validate", false, "Actively validate discovered secrets against provider APIs")
+	validateTimeout := flag.Duration("validate-timeout", 10*time.Second, "Timeout for each provider validation request")
+	crawl := flag.Bool("crawl", false, "Recursively crawl JS/sourcemap references reachable from each URL")
+	crawlDepth := flag.Int("crawl-depth", 2, "Maximum recursion depth for --crawl")
+	crawlScope := flag.String("crawl-scope", "", "Regex restricting --crawl expansion to matching hostnames")
+	configFile := flag.String("config", "", "TOML config file with defaults and per-target overrides")
+	profile := flag.String("profile", "", "Named [profiles.<name>] section to apply from --config")
+	reqRate := flag.Float64("rate", 0, "Global requests/sec limit, shared and per-host (0 = unlimited)")
+	maxRetries := flag.Int("max-retries", 3, "Maximum retries on 429/503 before giving up on a URL")
 	flag.Parse()
 
 	if *helpFlag {
@@ -349,6 +516,39 @@ func main() {
 		os.Exit(0)
 	}
 
+	// explicitlySet tracks which flags the user actually passed, so that
+	// file config values only fill in the ones left at their defaults:
+	// CLI flags always win over --config.
+	explicitlySet := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicitlySet[f.Name] = true })
+
+	var fileConfig *FileConfig
+	if *configFile != "" {
+		loaded, err := loadFileConfig(*configFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config file: %v\n", err)
+			os.Exit(1)
+		}
+		if err := loaded.applyProfile(*profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error applying profile: %v\n", err)
+			os.Exit(1)
+		}
+		fileConfig = loaded
+
+		if !explicitlySet["threads"] && loaded.Threads != 0 {
+			*threads = loaded.Threads
+		}
+		if !explicitlySet["format"] && loaded.Format != "" {
+			*format = loaded.Format
+		}
+		if !explicitlySet["output"] && loaded.Output != "" {
+			*outputFile = loaded.Output
+		}
+		if !explicitlySet["rate"] && loaded.Rate != 0 {
+			*reqRate = loaded.Rate
+		}
+	}
+
 	cfg := &Config{
 		threads:      *threads,
 		colorless:    *colorless,
@@ -357,6 +557,9 @@ func main() {
 		silent:       *silent,
 		sem:          semaphore.NewWeighted(*threads),
 		foundSecrets: sync.Map{},
+		fileConfig:   fileConfig,
+		rateLimiter:  NewRateLimiter(*reqRate, *silent),
+		maxRetries:   *maxRetries,
 	}
 
 	if *urlFile == "" {
@@ -373,6 +576,45 @@ func main() {
 	}
 	cfg.patterns = patterns
 
+	// Load scraper rules, if requested
+	if *scraperDir != "" {
+		scraper, err := NewScraper(*scraperDir, *scrapers)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading scraper rules: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.scraper = scraper
+	}
+
+	// Set up entropy-based detection, if requested
+	if *entropyEnabled {
+		var excludes []string
+		if *entropyExclude != "" {
+			excludes = strings.Split(*entropyExclude, ",")
+		}
+		entropy, err := NewEntropyDetector(*entropyB64Threshold, *entropyHexThreshold, *entropyMinLen, excludes)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring entropy detector: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.entropy = entropy
+	}
+
+	// Set up active validation, if requested
+	if *validate {
+		cfg.validators = NewValidatorSet(*validateTimeout)
+	}
+
+	// Set up recursive JS/sourcemap crawling, if requested
+	if *crawl {
+		crawler, err := NewCrawler(*crawlDepth, *crawlScope)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error configuring crawler: %v\n", err)
+			os.Exit(1)
+		}
+		cfg.crawler = crawler
+	}
+
 	// Read URLs
 	urls, err := readURLs(*urlFile)
 	if err != nil {
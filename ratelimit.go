@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	backoffBase         = 500 * time.Millisecond
+	backoffCap          = 30 * time.Second
+	hostShrinkThreshold = 3
+	hostShrinkCooldown  = 60 * time.Second
+)
+
+// hostState tracks one host's per-host token bucket plus the consecutive
+// 429/503 count used to decide when to shrink (and later restore) its rate.
+type hostState struct {
+	mu                  sync.Mutex
+	limiter             *rate.Limiter
+	consecutiveFailures int
+	shrunk              bool
+	shrunkAt            time.Time
+}
+
+// RateLimiter is a global token bucket plus a per-host token bucket map,
+// both consulted before every request, so a scan can't hammer a single
+// host even if the overall rate budget would allow it.
+type RateLimiter struct {
+	reqPerSec float64
+	global    *rate.Limiter
+	silent    bool
+
+	mu    sync.Mutex
+	hosts map[string]*hostState
+}
+
+// NewRateLimiter builds a limiter for reqPerSec requests/second (0 means
+// unlimited, in which case both buckets are no-ops).
+func NewRateLimiter(reqPerSec float64, silent bool) *RateLimiter {
+	rl := &RateLimiter{
+		reqPerSec: reqPerSec,
+		silent:    silent,
+		hosts:     make(map[string]*hostState),
+	}
+	if reqPerSec > 0 {
+		rl.global = rate.NewLimiter(rate.Limit(reqPerSec), burstFor(reqPerSec))
+	}
+	return rl
+}
+
+func burstFor(reqPerSec float64) int {
+	if burst := int(reqPerSec); burst > 1 {
+		return burst
+	}
+	return 1
+}
+
+func (rl *RateLimiter) stateFor(host string) *hostState {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	hs, ok := rl.hosts[host]
+	if !ok {
+		hs = &hostState{}
+		if rl.reqPerSec > 0 {
+			hs.limiter = rate.NewLimiter(rate.Limit(rl.reqPerSec), burstFor(rl.reqPerSec))
+		}
+		rl.hosts[host] = hs
+	}
+	return hs
+}
+
+// Wait blocks until both the global and per-host buckets allow a request,
+// restoring a previously shrunk host bucket once its cooldown has passed.
+func (rl *RateLimiter) Wait(ctx context.Context, host string) {
+	if rl.global != nil {
+		_ = rl.global.Wait(ctx)
+	}
+
+	hs := rl.stateFor(host)
+	hs.mu.Lock()
+	if hs.shrunk && time.Since(hs.shrunkAt) > hostShrinkCooldown {
+		hs.limiter = rate.NewLimiter(rate.Limit(rl.reqPerSec), burstFor(rl.reqPerSec))
+		hs.shrunk = false
+		hs.consecutiveFailures = 0
+		if !rl.silent {
+			fmt.Fprintf(os.Stderr, "[ratelimit] restoring %s to %.2f req/s after cooldown\n", host, rl.reqPerSec)
+		}
+	}
+	limiter := hs.limiter
+	hs.mu.Unlock()
+
+	if limiter != nil {
+		_ = limiter.Wait(ctx)
+	}
+}
+
+// RecordResponse shrinks a host's rate to half after hostShrinkThreshold
+// consecutive 429/503 responses, and resets the counter on anything else.
+func (rl *RateLimiter) RecordResponse(host string, statusCode int) {
+	if rl.reqPerSec <= 0 {
+		return
+	}
+
+	hs := rl.stateFor(host)
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	if statusCode != http.StatusTooManyRequests && statusCode != http.StatusServiceUnavailable {
+		hs.consecutiveFailures = 0
+		return
+	}
+
+	hs.consecutiveFailures++
+	if hs.shrunk || hs.consecutiveFailures < hostShrinkThreshold {
+		return
+	}
+
+	shrunkRate := rl.reqPerSec / 2
+	hs.limiter = rate.NewLimiter(rate.Limit(shrunkRate), burstFor(shrunkRate))
+	hs.shrunk = true
+	hs.shrunkAt = time.Now()
+	if !rl.silent {
+		fmt.Fprintf(os.Stderr, "[ratelimit] shrinking %s to %.2f req/s after sustained %d responses\n", host, shrunkRate, statusCode)
+	}
+}
+
+// decorrelatedJitter implements AWS's decorrelated-jitter backoff:
+// sleep = min(cap, random_between(base, prev*3)).
+func decorrelatedJitter(prev time.Duration) time.Duration {
+	upper := prev * 3
+	if upper < backoffBase {
+		upper = backoffBase
+	}
+	if upper > backoffCap {
+		upper = backoffCap
+	}
+
+	next := backoffBase + time.Duration(rand.Int63n(int64(upper-backoffBase+1)))
+	if next > backoffCap {
+		next = backoffCap
+	}
+	return next
+}
+
+// retryDelay honors a response's Retry-After header when present, falling
+// back to decorrelated jitter off of prevSleep.
+func retryDelay(resp *http.Response, prevSleep time.Duration) time.Duration {
+	if header := resp.Header.Get("Retry-After"); header != "" {
+		if seconds, err := strconv.Atoi(header); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+		if when, err := http.ParseTime(header); err == nil {
+			if d := time.Until(when); d > 0 {
+				return d
+			}
+		}
+	}
+	return decorrelatedJitter(prevSleep)
+}
+
+// doRequest sends req, applying the global/per-host rate limiter and
+// retrying with backoff on 429/503 up to cfg.maxRetries times.
+func (cfg *Config) doRequest(client *http.Client, req *http.Request) (*http.Response, error) {
+	sleep := backoffBase
+
+	for attempt := 0; ; attempt++ {
+		if cfg.rateLimiter != nil {
+			cfg.rateLimiter.Wait(req.Context(), req.URL.Host)
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			if cfg.rateLimiter != nil {
+				cfg.rateLimiter.RecordResponse(req.URL.Host, resp.StatusCode)
+			}
+			return resp, nil
+		}
+
+		if cfg.rateLimiter != nil {
+			cfg.rateLimiter.RecordResponse(req.URL.Host, resp.StatusCode)
+		}
+		resp.Body.Close()
+
+		if attempt >= cfg.maxRetries {
+			return resp, nil
+		}
+
+		sleep = retryDelay(resp, sleep)
+		if !cfg.silent {
+			fmt.Fprintf(os.Stderr, "[retry] %s returned %d, backing off %s (attempt %d/%d)\n",
+				req.URL, resp.StatusCode, sleep, attempt+1, cfg.maxRetries)
+		}
+		time.Sleep(sleep)
+	}
+}
@@ -0,0 +1,22 @@
+package main
+
+import "testing"
+
+func TestDecorrelatedJitterBounds(t *testing.T) {
+	prev := backoffBase
+	for i := 0; i < 100; i++ {
+		next := decorrelatedJitter(prev)
+		if next < backoffBase || next > backoffCap {
+			t.Fatalf("decorrelatedJitter(%v) = %v, want within [%v, %v]", prev, next, backoffBase, backoffCap)
+		}
+		prev = next
+	}
+}
+
+func TestDecorrelatedJitterCapsAtBackoffCap(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		if next := decorrelatedJitter(backoffCap * 10); next > backoffCap {
+			t.Fatalf("decorrelatedJitter(%v) = %v, want <= %v", backoffCap*10, next, backoffCap)
+		}
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/antchfx/htmlquery"
+	"github.com/oliveagle/jsonpath"
+	"gopkg.in/yaml.v3"
+)
+
+// ScraperRule describes one extraction rule loaded from --scraper-dir.
+// It mirrors the shape of ffuf's scraper rules: a single expression of a
+// given Type, evaluated against a Target, with optional downstream regexes
+// (OnMatch) chained over whatever fragment the rule extracts.
+type ScraperRule struct {
+	Name    string   `json:"name" yaml:"name"`
+	Rule    string   `json:"rule" yaml:"rule"`
+	Type    string   `json:"type" yaml:"type"`     // regex | query | xpath | json
+	Target  string   `json:"target" yaml:"target"` // body | headers
+	Action  string   `json:"action" yaml:"action"` // log | store
+	OnMatch []string `json:"on_match" yaml:"on_match"`
+}
+
+// Scraper evaluates a set of ScraperRules against a fetched document and
+// feeds whatever it finds back into the normal Result pipeline.
+type Scraper struct {
+	rules []ScraperRule
+}
+
+// loadScraperRules reads one rule per .json/.yaml/.yml file in dir.
+func loadScraperRules(dir string) ([]ScraperRule, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading scraper directory: %v", err)
+	}
+
+	var rules []ScraperRule
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading scraper rule %s: %v", path, err)
+		}
+
+		var rule ScraperRule
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json":
+			err = json.Unmarshal(data, &rule)
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(data, &rule)
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing scraper rule %s: %v", path, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// NewScraper loads the rules in dir and filters them down to selected,
+// which is either "all" or a comma-separated list of rule names.
+func NewScraper(dir, selected string) (*Scraper, error) {
+	rules, err := loadScraperRules(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if selected == "" || strings.EqualFold(selected, "all") {
+		return &Scraper{rules: rules}, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range strings.Split(selected, ",") {
+		wanted[strings.TrimSpace(name)] = true
+	}
+
+	var active []ScraperRule
+	for _, rule := range rules {
+		if wanted[rule.Name] {
+			active = append(active, rule)
+		}
+	}
+
+	return &Scraper{rules: active}, nil
+}
+
+// Run evaluates every active rule against targetURL's response and returns
+// the Results it produced (including anything surfaced by OnMatch chains).
+func (s *Scraper) Run(cfg *Config, targetURL string, headers http.Header, body []byte, start time.Time) []Result {
+	var results []Result
+
+	for _, rule := range s.rules {
+		var fragments []string
+
+		switch rule.Target {
+		case "headers":
+			for name, values := range headers {
+				for _, value := range values {
+					fragments = append(fragments, extractFragments(rule, name+": "+value)...)
+				}
+			}
+		default: // "body"
+			fragments = extractFragments(rule, string(body))
+		}
+
+		for _, fragment := range fragments {
+			results = append(results, s.applyOnMatch(cfg, rule, targetURL, fragment, start)...)
+		}
+	}
+
+	return results
+}
+
+// extractFragments runs a single rule's expression against content,
+// dispatching on the rule Type.
+func extractFragments(rule ScraperRule, content string) []string {
+	switch rule.Type {
+	case "query":
+		return queryFragments(rule.Rule, content)
+	case "xpath":
+		return xpathFragments(rule.Rule, content)
+	case "json":
+		return jsonFragments(rule.Rule, content)
+	default: // "regex"
+		regex, err := regexp.Compile(rule.Rule)
+		if err != nil {
+			return nil
+		}
+		return regex.FindAllString(content, -1)
+	}
+}
+
+func queryFragments(selector, content string) []string {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	var fragments []string
+	doc.Find(selector).Each(func(_ int, sel *goquery.Selection) {
+		if src, ok := sel.Attr("src"); ok {
+			fragments = append(fragments, src)
+			return
+		}
+		if href, ok := sel.Attr("href"); ok {
+			fragments = append(fragments, href)
+			return
+		}
+		fragments = append(fragments, sel.Text())
+	})
+
+	return fragments
+}
+
+func xpathFragments(expr, content string) []string {
+	doc, err := htmlquery.Parse(strings.NewReader(content))
+	if err != nil {
+		return nil
+	}
+
+	nodes, err := htmlquery.QueryAll(doc, expr)
+	if err != nil {
+		return nil
+	}
+
+	var fragments []string
+	for _, node := range nodes {
+		fragments = append(fragments, htmlquery.InnerText(node))
+	}
+
+	return fragments
+}
+
+func jsonFragments(path, content string) []string {
+	var data interface{}
+	if err := json.Unmarshal([]byte(content), &data); err != nil {
+		return nil
+	}
+
+	res, err := jsonpath.JsonPathLookup(data, path)
+	if err != nil {
+		return nil
+	}
+
+	switch v := res.(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		var fragments []string
+		for _, item := range v {
+			fragments = append(fragments, fmt.Sprintf("%v", item))
+		}
+		return fragments
+	default:
+		return []string{fmt.Sprintf("%v", v)}
+	}
+}
+
+// applyOnMatch chains a rule's OnMatch regexes over an extracted fragment.
+// With no OnMatch chain, the fragment itself is surfaced as a Result.
+// Otherwise, if the fragment resolves to a URL that fetches successfully,
+// the OnMatch regexes run over that URL's body; if not, they run over the
+// fragment itself. Each OnMatch match is surfaced as a Result.
+func (s *Scraper) applyOnMatch(cfg *Config, rule ScraperRule, sourceURL, fragment string, start time.Time) []Result {
+	if len(rule.OnMatch) == 0 {
+		return []Result{{
+			Type:          "scraper:" + rule.Name,
+			Data:          fragment,
+			URL:           sourceURL,
+			TimeUsed:      time.Since(start),
+			scraperAction: rule.Action,
+		}}
+	}
+
+	var results []Result
+	resolved := resolveScraperURL(sourceURL, fragment)
+	content := fragment
+
+	if resolved != "" {
+		if body, err := fetchBody(cfg, resolved); err == nil {
+			content = string(body)
+			sourceURL = resolved
+		}
+	}
+
+	for _, pattern := range rule.OnMatch {
+		regex, err := regexp.Compile(pattern)
+		if err != nil {
+			continue
+		}
+		for _, match := range regex.FindAllString(content, -1) {
+			results = append(results, Result{
+				Type:          "scraper:" + rule.Name,
+				Data:          match,
+				URL:           sourceURL,
+				TimeUsed:      time.Since(start),
+				scraperAction: rule.Action,
+			})
+		}
+	}
+
+	return results
+}
+
+func resolveScraperURL(base, ref string) string {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	refURL, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	if !refURL.IsAbs() && refURL.Host == "" && refURL.Path == "" {
+		return ""
+	}
+	return baseURL.ResolveReference(refURL).String()
+}
+
+// fetchBody is used by scraper OnMatch chains to pull down a
+// fragment-derived URL for scanning. It goes through the same
+// newClient/cfg.doRequest path as processURL and crawl.go's
+// fetchAndScan, so OnMatch-chained fetches still honor --config's
+// per-target proxy/headers/cookies/insecure_tls and the global/per-host
+// rate limiter and 429/503 backoff, instead of hammering the target at
+// full, unthrottled speed.
+func fetchBody(cfg *Config, target string) ([]byte, error) {
+	settings := TargetSettings{InsecureTLS: true}
+	if cfg.fileConfig != nil {
+		settings = cfg.fileConfig.settingsFor(target)
+	}
+
+	client, err := newClient(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range settings.Headers {
+		req.Header.Set(name, value)
+	}
+	for name, value := range settings.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	resp, err := cfg.doRequest(client, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
+}
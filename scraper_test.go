@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestResolveScraperURLAbsolute(t *testing.T) {
+	got := resolveScraperURL("https://example.com/page", "https://other.com/app.js")
+	want := "https://other.com/app.js"
+	if got != want {
+		t.Errorf("resolveScraperURL = %q, want %q", got, want)
+	}
+}
+
+func TestResolveScraperURLRelative(t *testing.T) {
+	got := resolveScraperURL("https://example.com/dir/page.html", "chunk.js")
+	want := "https://example.com/dir/chunk.js"
+	if got != want {
+		t.Errorf("resolveScraperURL = %q, want %q", got, want)
+	}
+}
+
+func TestResolveScraperURLRejectsEmptyFragment(t *testing.T) {
+	if got := resolveScraperURL("https://example.com/page", "#just-an-anchor"); got != "" {
+		t.Errorf("resolveScraperURL = %q, want empty for a fragment-only reference", got)
+	}
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// Status is the outcome of actively validating a discovered credential
+// against its issuing provider.
+type Status string
+
+const (
+	StatusActive   Status = "Active"
+	StatusInactive Status = "Inactive"
+	StatusUnknown  Status = "Unknown"
+)
+
+// Validator confirms whether a matched credential is still live.
+type Validator interface {
+	Supports(patternName string) bool
+	// ProviderHost is the host Validate actually calls (e.g.
+	// "api.github.com"), used to key the per-provider rate limiter. It is
+	// NOT the host of the scanned target, which has nothing to do with how
+	// hard we hit the provider's API.
+	ProviderHost() string
+	Validate(ctx context.Context, secret string) (Status, map[string]string, error)
+}
+
+// ValidatorSet runs the registered Validators against a match, rate
+// limiting requests per provider host so validation does not itself trip
+// abuse detection.
+type ValidatorSet struct {
+	validators []Validator
+	timeout    time.Duration
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewValidatorSet builds the default set of provider validators.
+//
+// AWS and Twilio secrets are matched by a single regex with no paired
+// counterpart (an AWS access key without its secret key, a Twilio auth
+// token without its Account SID), so neither can complete a real signed
+// call (SigV4 / Basic auth) from the match alone. Both validators below
+// only confirm the match *looks* well-formed and otherwise report
+// StatusUnknown -- see --help's --validate entry for the same caveat.
+func NewValidatorSet(timeout time.Duration) *ValidatorSet {
+	return &ValidatorSet{
+		timeout: timeout,
+		validators: []Validator{
+			awsValidator{},
+			githubValidator{},
+			gitlabValidator{},
+			slackValidator{},
+			stripeValidator{},
+			sendgridValidator{},
+			twilioValidator{},
+			jwtValidator{},
+		},
+		limiters: make(map[string]*rate.Limiter),
+	}
+}
+
+func (vs *ValidatorSet) limiterFor(host string) *rate.Limiter {
+	vs.mu.Lock()
+	defer vs.mu.Unlock()
+
+	limiter, ok := vs.limiters[host]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Every(time.Second), 1)
+		vs.limiters[host] = limiter
+	}
+	return limiter
+}
+
+// Validate finds the first Validator that supports patternName and runs it,
+// returning StatusUnknown if no validator is registered for that pattern.
+// Rate limiting is keyed on the provider's own host (e.g.
+// "api.github.com"), not the host of the URL that was scanned, so that
+// validating tokens found across many different scanned targets still
+// shares one limiter per provider.
+func (vs *ValidatorSet) Validate(patternName, secret string) (Status, map[string]string) {
+	for _, validator := range vs.validators {
+		if !validator.Supports(patternName) {
+			continue
+		}
+
+		_ = vs.limiterFor(validator.ProviderHost()).Wait(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), vs.timeout)
+		status, meta, err := validator.Validate(ctx, secret)
+		cancel()
+		if err != nil {
+			return StatusUnknown, map[string]string{"error": err.Error()}
+		}
+		return status, meta
+	}
+
+	return StatusUnknown, nil
+}
+
+func httpJSONGet(ctx context.Context, url string, headers map[string]string) (int, map[string]interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	var body map[string]interface{}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode, body, nil
+}
+
+// --- AWS ---
+
+type awsValidator struct{}
+
+func (awsValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "aws")
+}
+
+func (awsValidator) ProviderHost() string {
+	return "sts.amazonaws.com"
+}
+
+// Validate cannot complete a signed STS GetCallerIdentity call: that needs
+// the access key's paired secret key, which a single regex match never
+// carries. All it can do is confirm the key *looks* like a real AWS access
+// key ID; it never reports Active.
+func (awsValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	if !strings.HasPrefix(secret, "AKIA") && !strings.HasPrefix(secret, "ASIA") {
+		return StatusUnknown, map[string]string{"reason": "does not look like an AWS access key ID"}, nil
+	}
+	return StatusUnknown, map[string]string{"reason": "paired secret key required for a signed STS GetCallerIdentity call"}, nil
+}
+
+// --- GitHub ---
+
+type githubValidator struct{}
+
+func (githubValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "github")
+}
+
+func (githubValidator) ProviderHost() string {
+	return "api.github.com"
+}
+
+func (githubValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	status, body, err := httpJSONGet(ctx, "https://api.github.com/user", map[string]string{
+		"Authorization": "token " + secret,
+	})
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	if status == http.StatusOK {
+		meta := map[string]string{}
+		if login, ok := body["login"].(string); ok {
+			meta["login"] = login
+		}
+		return StatusActive, meta, nil
+	}
+	return StatusInactive, nil, nil
+}
+
+// --- GitLab ---
+
+type gitlabValidator struct{}
+
+func (gitlabValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "gitlab")
+}
+
+func (gitlabValidator) ProviderHost() string {
+	return "gitlab.com"
+}
+
+func (gitlabValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	status, body, err := httpJSONGet(ctx, "https://gitlab.com/api/v4/user", map[string]string{
+		"PRIVATE-TOKEN": secret,
+	})
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	if status == http.StatusOK {
+		meta := map[string]string{}
+		if username, ok := body["username"].(string); ok {
+			meta["username"] = username
+		}
+		return StatusActive, meta, nil
+	}
+	return StatusInactive, nil, nil
+}
+
+// --- Slack ---
+
+type slackValidator struct{}
+
+func (slackValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "slack")
+}
+
+func (slackValidator) ProviderHost() string {
+	return "slack.com"
+}
+
+func (slackValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://slack.com/api/auth.test", nil)
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+secret)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		OK   bool   `json:"ok"`
+		Team string `json:"team"`
+		User string `json:"user"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+
+	if body.OK {
+		return StatusActive, map[string]string{"team": body.Team, "user": body.User}, nil
+	}
+	return StatusInactive, nil, nil
+}
+
+// --- Stripe ---
+
+type stripeValidator struct{}
+
+func (stripeValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "stripe")
+}
+
+func (stripeValidator) ProviderHost() string {
+	return "api.stripe.com"
+}
+
+func (stripeValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	status, _, err := httpJSONGet(ctx, "https://api.stripe.com/v1/charges?limit=1", map[string]string{
+		"Authorization": "Bearer " + secret,
+	})
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	if status == http.StatusOK {
+		return StatusActive, nil, nil
+	}
+	if status == http.StatusUnauthorized {
+		return StatusInactive, nil, nil
+	}
+	return StatusUnknown, nil, nil
+}
+
+// --- SendGrid ---
+
+type sendgridValidator struct{}
+
+func (sendgridValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "sendgrid")
+}
+
+func (sendgridValidator) ProviderHost() string {
+	return "api.sendgrid.com"
+}
+
+func (sendgridValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	status, _, err := httpJSONGet(ctx, "https://api.sendgrid.com/v3/scopes", map[string]string{
+		"Authorization": "Bearer " + secret,
+	})
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	if status == http.StatusOK {
+		return StatusActive, nil, nil
+	}
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return StatusInactive, nil, nil
+	}
+	return StatusUnknown, nil, nil
+}
+
+// --- Twilio ---
+
+type twilioValidator struct{}
+
+func (twilioValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "twilio")
+}
+
+func (twilioValidator) ProviderHost() string {
+	return "api.twilio.com"
+}
+
+// Validate cannot complete Twilio's Basic auth (Account SID + auth token):
+// a single regex match only ever carries one half of that pair. It never
+// reports Active, only that the half it has is present.
+func (twilioValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	return StatusUnknown, map[string]string{"reason": "Account SID required alongside auth token for Basic auth"}, nil
+}
+
+// --- JWT ---
+
+type jwtValidator struct{}
+
+func (jwtValidator) Supports(patternName string) bool {
+	return strings.Contains(strings.ToLower(patternName), "jwt")
+}
+
+// ProviderHost returns "" because a JWT's issuer -- and therefore the host
+// its kid fetch below calls -- varies per secret, not per validator type,
+// so there is no single constant host to key the rate limiter on the way
+// the other validators do.
+func (jwtValidator) ProviderHost() string {
+	return ""
+}
+
+// Validate decodes the header and payload and checks expiry, then
+// corroborates the header's kid against the issuer's published JWKS before
+// ever reporting Active. An unexpired exp claim alone proves nothing about
+// a forged token with no valid signature, and fully verifying the
+// signature needs the issuer's public key material and algorithm-specific
+// crypto that's out of scope here -- but reporting Active on exp alone
+// would be actively misleading, so a token whose kid can't be corroborated
+// is reported Unknown instead.
+func (jwtValidator) Validate(ctx context.Context, secret string) (Status, map[string]string, error) {
+	parts := strings.Split(secret, ".")
+	if len(parts) != 3 {
+		return StatusUnknown, nil, fmt.Errorf("not a well-formed JWT")
+	}
+
+	headerRaw, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+	var header struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(headerRaw, &header); err != nil {
+		return StatusUnknown, nil, err
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return StatusUnknown, nil, err
+	}
+
+	var claims struct {
+		Exp int64  `json:"exp"`
+		Sub string `json:"sub"`
+		Iss string `json:"iss"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return StatusUnknown, nil, err
+	}
+
+	meta := map[string]string{"sub": claims.Sub}
+	if claims.Exp != 0 {
+		meta["exp"] = time.Unix(claims.Exp, 0).UTC().Format(time.RFC3339)
+	}
+	if claims.Exp != 0 && time.Now().Unix() >= claims.Exp {
+		return StatusInactive, meta, nil
+	}
+
+	if header.Kid == "" || claims.Iss == "" {
+		meta["reason"] = "no kid/iss claim to corroborate against the issuer's JWKS"
+		return StatusUnknown, meta, nil
+	}
+
+	status, body, err := httpJSONGet(ctx, strings.TrimRight(claims.Iss, "/")+"/.well-known/jwks.json", nil)
+	if err != nil || status != http.StatusOK {
+		meta["reason"] = "issuer JWKS unreachable, kid unverified"
+		return StatusUnknown, meta, nil
+	}
+
+	keys, _ := body["keys"].([]interface{})
+	for _, k := range keys {
+		if km, ok := k.(map[string]interface{}); ok {
+			if kid, _ := km["kid"].(string); kid == header.Kid {
+				meta["kid"] = header.Kid
+				return StatusActive, meta, nil
+			}
+		}
+	}
+
+	meta["reason"] = "kid not found in issuer JWKS"
+	return StatusUnknown, meta, nil
+}